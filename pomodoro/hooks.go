@@ -0,0 +1,67 @@
+package pomodoro
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// hookTimeout bounds how long a single OnEvent command may run before it
+// is killed, so a hung script can't block the ticker.
+const hookTimeout = 5 * time.Second
+
+// eventKeys returns the OnEvent keys that fire for event. EventStart fires
+// both the generic "start" key and a category-specific one, so users can
+// hook either "anything started" or "a break started" separately.
+func eventKeys(event Event, i Interval) []string {
+	switch event {
+	case EventStart:
+		if i.Category == CategoryPomodoro {
+			return []string{"start", "pomodoro_start"}
+		}
+		return []string{"start", "break_start"}
+	case EventPause:
+		return []string{"pause"}
+	case EventResume:
+		return []string{"resume"}
+	case EventExpire:
+		return []string{"end"}
+	case EventCancel:
+		return []string{"cancel"}
+	default:
+		return nil
+	}
+}
+
+// runHooks launches every command configured under key in its own
+// goroutine, with the interval's metadata exported as environment
+// variables. It never blocks and never returns an error: a misbehaving
+// hook must not affect the interval it was configured against.
+func runHooks(config *IntervalConfig, key string, i Interval) {
+	for _, command := range config.OnEvent[key] {
+		go runHook(command, hookEnv(i))
+	}
+}
+
+func hookEnv(i Interval) []string {
+	return []string{
+		fmt.Sprintf("POMO_CATEGORY=%s", i.Category),
+		fmt.Sprintf("POMO_PLANNED=%s", i.PlannedDuration),
+		fmt.Sprintf("POMO_ACTUAL=%s", i.ActualDuration),
+		fmt.Sprintf("POMO_ID=%d", i.ID),
+		fmt.Sprintf("POMO_STATE=%d", i.State),
+	}
+}
+
+func runHook(command string, env []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(cmd.Environ(), env...)
+
+	// Best effort: a failing or hung hook is killed by the context
+	// deadline and its error discarded.
+	_ = cmd.Run()
+}
@@ -29,18 +29,45 @@ type Interval struct {
 	StartTime       time.Time
 	PlannedDuration time.Duration
 	ActualDuration  time.Duration
+	PauseDuration   time.Duration
+	Pauses          []PauseSegment
 	Category        string
 	State 		    int
 }
 
+// PauseSegment records one contiguous span during which an interval sat in
+// StatePaused, so stats can reconstruct when breaks away from the timer
+// happened, not just how long they totalled.
+type PauseSegment struct {
+	Start time.Time
+	End   time.Time
+}
+
+// TimePaused returns the total time the interval has spent in StatePaused
+// across its finished pauses. A pause still in progress is not counted
+// until it ends.
+func (i Interval) TimePaused() time.Duration {
+	return i.PauseDuration
+}
+
 type Repository interface {
 	Create(i Interval) (int64, error)
 	Update(i Interval) error
 	ByID(id int64) (Interval, error)
 	Last() (Interval, error)
 	Breaks(n int) ([]Interval, error)
+	// CountSince returns how many intervals of category have started at or
+	// after t, for schedulers (e.g. DailyBudgetScheduler) that ration
+	// pomodoros over a calendar window rather than a fixed count.
+	CountSince(t time.Time, category string) (int, error)
 }
 
+// SchemaVersion identifies the shape of the data Repository implementations
+// persist. Bump it whenever Interval's fields change - SQL-backed
+// repositories use it to decide which migrations to run. It went to 2 when
+// PauseDuration and Pauses were added.
+const SchemaVersion = 2
+
 // custom errors that may occur in business logic
 var (
 	ErrNoIntervals = errors.New("No intervals")
@@ -56,10 +83,21 @@ type IntervalConfig struct {
 	PomodoroDuration   time.Duration
 	ShortBreakDuration time.Duration
 	LongBreakDuration  time.Duration
+	// OnEvent maps a lifecycle key ("start", "pause", "resume", "end",
+	// "cancel", "break_start", "pomodoro_start") to shell commands run
+	// whenever that event fires, so users can wire up notifiers, DND
+	// toggles or webhooks without patching this package.
+	OnEvent map[string][]string
+	// Scheduler decides the category (and its duration) of the next
+	// interval, replacing the hard-coded "long break every 4 pomodoros"
+	// rule with a pluggable policy.
+	Scheduler Scheduler
 }
 
-// instantiate new IntervalConfig
-func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration) *IntervalConfig {
+// instantiate new IntervalConfig. A nil scheduler defaults to a
+// CountScheduler that inserts a long break every 4 pomodoros, matching the
+// prior hard-coded behavior.
+func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration, scheduler Scheduler) *IntervalConfig {
 	config := &IntervalConfig{
 		repo: repo,
 		PomodoroDuration:   25 * time.Minute,
@@ -73,6 +111,18 @@ func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration) *
 
 	if longBreak > 0 { config.LongBreakDuration = longBreak }
 
+	if scheduler == nil {
+		scheduler = &CountScheduler{
+			LongBreakEvery: 4,
+			Durations: SchedulerDurations{
+				Pomodoro:   config.PomodoroDuration,
+				ShortBreak: config.ShortBreakDuration,
+				LongBreak:  config.LongBreakDuration,
+			},
+		}
+	}
+	config.Scheduler = scheduler
+
 	return config
 }
 /**
@@ -84,6 +134,13 @@ func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration) *
 * and after four Pomodoros, there’s a long break.
 */
 func nextCategory(r Repository) (string, error) {
+	return nextCategoryN(r, 4)
+}
+
+// nextCategoryN is nextCategory generalized to an arbitrary long-break
+// cadence: a long break follows every longBreakEvery-1 short breaks taken
+// in a row. It backs CountScheduler.
+func nextCategoryN(r Repository, longBreakEvery int) (string, error) {
 	lastInterval, err := r.Last()
 	if err != nil && err == ErrNoIntervals {
 		return CategoryPomodoro, nil
@@ -97,13 +154,15 @@ func nextCategory(r Repository) (string, error) {
 		return CategoryPomodoro, nil
 	}
 
-	lastBreaks, err := r.Breaks(3)
+	n := longBreakEvery - 1
+
+	lastBreaks, err := r.Breaks(n)
 	if err != nil {
 		return "", err
 	}
 
-	if len(lastBreaks) < 3 {
-		return CategoryShortBreak, err
+	if len(lastBreaks) < n {
+		return CategoryShortBreak, nil
 	}
 
 	for _, i := range lastBreaks {
@@ -115,64 +174,31 @@ func nextCategory(r Repository) (string, error) {
 	return CategoryLongBreak, nil
 }
 
-// used to perform tasks while the interval executes
-type Callback func(Interval)
-
-/**
-* This function uses the time.Ticker type and a loop to execute actions every
-* second while the interval time progresses. It uses a select statement to take
-* actions, executing periodically when the time.Ticker goes off,
-* finishing successfully when the interval time expires
-* or canceling when a signal is received from Context.
-*/
-func tick(ctx context.Context, id int64, config *IntervalConfig, start, periodic, end Callback) error {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	interval, err := config.repo.ByID(id)
+// TimeSinceLastBreak reports how long it has been since the most recently
+// completed break, for a "time since last break" statistic. It returns
+// ErrNoIntervals if no break has ever been recorded.
+func TimeSinceLastBreak(r Repository) (time.Duration, error) {
+	breaks, err := r.Breaks(1)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	expire := time.After(interval.PlannedDuration - interval.ActualDuration)
-
-	start(interval)
-
-	for {
-		select {
-		case <- ticker.C:
-			interval, err := config.repo.ByID(id)
-			if err != nil {
-				return err
-			}
-
-			if interval.State == StatePaused {
-				return nil
-			}
-
-			interval.ActualDuration += time.Second
-			if err := config.repo.Update(interval); err != nil {
-				return err
-			}
-
-			periodic(interval)
-		case <- expire:
-			interval, err := config.repo.ByID(id)
-			if err != nil {
-				return err
-			}
-
-			interval.State = StateDone
-
-			end(interval)
-
-			return config.repo.Update(interval)
-		case <- ctx.Done():
-			interval, err := config.repo.ByID(id)
-			if err != nil {
-				return err
-			}
-			interval.State = StateCancelled
-		}
+	if len(breaks) == 0 {
+		return 0, ErrNoIntervals
 	}
+
+	last := breaks[0]
+	return time.Since(last.StartTime.Add(last.ActualDuration)), nil
+}
+
+// used to perform tasks while the interval executes
+type Callback func(Interval)
+
+// tick is kept as the package's original entry point for running an
+// interval to completion. It now delegates to a throwaway Runner; callers
+// that need to observe transitions (OnTransition) or react to Pause/Resume
+// from outside the loop should construct their own Runner via NewRunner
+// and call Run directly instead.
+func tick(ctx context.Context, id int64, config *IntervalConfig, start, periodic, end Callback) error {
+	return NewRunner(id, config).Run(ctx, start, periodic, end)
 }
\ No newline at end of file
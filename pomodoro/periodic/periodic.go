@@ -0,0 +1,103 @@
+// Package periodic implements a small ticker+context+callback primitive:
+// run a callback on a fixed interval until cancelled, with an optional
+// immediate first fire and a guaranteed final callback on stop. It exists
+// so the repeated "NewTicker plus a select loop" pattern only has to be
+// gotten right once.
+package periodic
+
+import (
+	"context"
+	"time"
+)
+
+// Tick describes a single firing of a periodic task.
+type Tick struct {
+	Elapsed time.Duration
+	Time    time.Time
+}
+
+// Callback is invoked on every tick, and, depending on the options passed
+// to Start, once immediately and once more on stop.
+type Callback func(Tick)
+
+// Stopper stops a running periodic task. Stop blocks until any callback
+// already in flight, and the OnStop callback if one was configured, have
+// finished running.
+type Stopper interface {
+	Stop()
+}
+
+type options struct {
+	immediate bool
+	onStop    Callback
+}
+
+// Option configures a call to Start.
+type Option func(*options)
+
+// Immediate makes the task call cb once, on its own goroutine, before the
+// first tick fires. Start does not wait for that call.
+func Immediate() Option {
+	return func(o *options) { o.immediate = true }
+}
+
+// OnStop registers fn to run exactly once when the periodic task stops,
+// whether that's because ctx was cancelled or Stop was called directly.
+// It's how a caller persists a final state uniformly instead of
+// duplicating that logic at every call site that might stop the task.
+func OnStop(fn Callback) Option {
+	return func(o *options) { o.onStop = fn }
+}
+
+type task struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Stop implements Stopper.
+func (t *task) Stop() {
+	t.cancel()
+	<-t.done
+}
+
+// Start runs cb every interval, on its own goroutine, until ctx is done
+// or the returned Stopper's Stop method is called. Ticks are delivered
+// one at a time from a single goroutine, so a slow cb can never overlap
+// with the next tick.
+func Start(ctx context.Context, interval time.Duration, cb Callback, opts ...Option) Stopper {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	t := &task{cancel: cancel, done: make(chan struct{})}
+
+	start := time.Now()
+
+	go func() {
+		defer close(t.done)
+
+		if o.immediate {
+			cb(Tick{Time: start})
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				cb(Tick{Elapsed: now.Sub(start), Time: now})
+			case <-ctx.Done():
+				if o.onStop != nil {
+					now := time.Now()
+					o.onStop(Tick{Elapsed: now.Sub(start), Time: now})
+				}
+				return
+			}
+		}
+	}()
+
+	return t
+}
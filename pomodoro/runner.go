@@ -0,0 +1,305 @@
+package pomodoro
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vpaklatzis/pomodoro-cli-app-in-go/pomodoro/periodic"
+)
+
+// Event represents a request to move a Runner from one state to another.
+type Event int
+
+const (
+	EventStart Event = iota
+	EventPause
+	EventResume
+	EventTick
+	EventExpire
+	EventCancel
+)
+
+// TransitionFunc is called after a Runner successfully changes state, so
+// callers can observe the change or hang side effects off it.
+type TransitionFunc func(from, to int, i Interval)
+
+// transitions enumerates, for every state, which events are legal from it
+// and which state they lead to. Anything not listed here is rejected with
+// ErrInvalidState.
+var transitions = map[int]map[Event]int{
+	StateNotStarted: {
+		EventStart: StateRunning,
+	},
+	StateRunning: {
+		EventTick:   StateRunning,
+		EventPause:  StatePaused,
+		EventExpire: StateDone,
+		EventCancel: StateCancelled,
+	},
+	StatePaused: {
+		EventResume: StateRunning,
+		EventCancel: StateCancelled,
+	},
+}
+
+// Runner drives a single Interval through its lifecycle. Instead of tick()
+// reading and writing repository state ad hoc, it sends the Runner events
+// and the Runner validates, persists and reports the resulting transition.
+//
+// A Runner is safe for concurrent use: mu serializes every ByID/Update
+// sequence against the interval's row, and Pause/Resume/Cancel never touch
+// the repository themselves - they enqueue onto events, which only Run's
+// per-tick callback ever drains. That keeps a UI goroutine calling
+// Pause from racing the ticker goroutine's read-modify-write of
+// ActualDuration and State.
+type Runner struct {
+	id           int64
+	config       *IntervalConfig
+	state        int
+	onTransition TransitionFunc
+	pausedAt     time.Time
+
+	mu     sync.Mutex
+	events chan Event
+}
+
+// eventQueueSize is large enough that a user mashing pause/resume never
+// has a request silently dropped under realistic UI usage.
+const eventQueueSize = 8
+
+// NewRunner creates a Runner for the interval identified by id, starting
+// in StateNotStarted.
+func NewRunner(id int64, config *IntervalConfig) *Runner {
+	return &Runner{
+		id:     id,
+		config: config,
+		state:  StateNotStarted,
+		events: make(chan Event, eventQueueSize),
+	}
+}
+
+// OnTransition registers fn to be invoked after every transition the
+// Runner makes. Only one hook is kept; calling it again replaces the
+// previous one.
+func (r *Runner) OnTransition(fn TransitionFunc) {
+	r.onTransition = fn
+}
+
+// Pause enqueues a pause request for the running Run loop to apply on a
+// later tick; Run applies queued requests one per tick, in order, and a
+// request is dropped if the queue is full. It is safe to call from any
+// goroutine.
+func (r *Runner) Pause() { r.enqueue(EventPause) }
+
+// Resume enqueues a resume request, queued and applied like Pause's. It is
+// safe to call from any goroutine.
+func (r *Runner) Resume() { r.enqueue(EventResume) }
+
+// Cancel enqueues a cancel request, queued and applied like Pause's. It is
+// safe to call from any goroutine.
+func (r *Runner) Cancel() { r.enqueue(EventCancel) }
+
+func (r *Runner) enqueue(event Event) {
+	select {
+	case r.events <- event:
+	default:
+		// Queue is full; a UI is sending events faster than Run can
+		// apply them, and dropping one here is preferable to blocking
+		// the caller or growing without bound.
+	}
+}
+
+// dequeue returns the next pending event, if any, without blocking.
+func (r *Runner) dequeue() (Event, bool) {
+	select {
+	case event := <-r.events:
+		return event, true
+	default:
+		return 0, false
+	}
+}
+
+// currentState returns the Runner's state under mu, so callers racing
+// Transition still see a consistent value.
+func (r *Runner) currentState() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// mutate reads the interval, applies fn to it and persists the result,
+// all while holding mu. Every read-modify-write of the interval's row -
+// not just Transition's - goes through this, so the per-tick
+// ActualDuration bump can't interleave with a concurrent Transition (e.g.
+// a Pause event drained on the very same tick) and lose a write.
+func (r *Runner) mutate(fn func(Interval) Interval) (Interval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interval, err := r.config.repo.ByID(r.id)
+	if err != nil {
+		return Interval{}, err
+	}
+
+	interval = fn(interval)
+
+	if err := r.config.repo.Update(interval); err != nil {
+		return Interval{}, err
+	}
+
+	return interval, nil
+}
+
+// lockedByID reads the interval's current row while holding mu, so it
+// can't observe a half-applied Transition from another goroutine.
+func (r *Runner) lockedByID() (Interval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config.repo.ByID(r.id)
+}
+
+// Transition moves the Runner from its current state to the state that
+// event leads to, persisting the updated Interval via the repository. It
+// returns ErrInvalidState if event is not legal from the current state.
+// The repository read-modify-write is serialized by mu, so two goroutines
+// transitioning the same Runner can never interleave their Update calls.
+func (r *Runner) Transition(event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, ok := transitions[r.state][event]
+	if !ok {
+		return ErrInvalidState
+	}
+
+	interval, err := r.config.repo.ByID(r.id)
+	if err != nil {
+		return err
+	}
+
+	from := r.state
+	interval.State = next
+
+	switch {
+	case event == EventPause:
+		r.pausedAt = time.Now()
+	case from == StatePaused:
+		elapsed := time.Since(r.pausedAt)
+		interval.PauseDuration += elapsed
+		interval.Pauses = append(interval.Pauses, PauseSegment{Start: r.pausedAt, End: r.pausedAt.Add(elapsed)})
+	}
+
+	if err := r.config.repo.Update(interval); err != nil {
+		return err
+	}
+
+	r.state = next
+
+	if r.onTransition != nil {
+		r.onTransition(from, next, interval)
+	}
+
+	for _, key := range eventKeys(event, interval) {
+		runHooks(r.config, key, interval)
+	}
+
+	return nil
+}
+
+// Run drives the Runner's interval to completion or cancellation, calling
+// start once at the beginning, onTick on every elapsed second the interval
+// is running, and end when the planned duration expires. The per-second
+// heartbeat is delegated to periodic.Start, whose OnStop hook is what
+// guarantees StateCancelled gets persisted when ctx is cancelled - the old
+// tick() loop set that field in memory and then returned without an
+// Update call. Pause/Resume/Cancel calls from other goroutines never touch
+// the repository directly: they land in the event queue drained here, on
+// the single goroutine that owns this interval's ByID/Update sequence.
+func (r *Runner) Run(ctx context.Context, start, onTick, end Callback) error {
+	interval, err := r.lockedByID()
+	if err != nil {
+		return err
+	}
+
+	if err := r.Transition(EventStart); err != nil {
+		return err
+	}
+
+	start(interval)
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	done := make(chan error, 1)
+	var settled bool
+
+	stopper := periodic.Start(runCtx, time.Second, func(periodic.Tick) {
+		if event, ok := r.dequeue(); ok {
+			if err := r.Transition(event); err != nil && err != ErrInvalidState {
+				done <- err
+				cancelRun()
+				return
+			}
+
+			if event == EventCancel && r.currentState() == StateCancelled {
+				settled = true
+				done <- nil
+				cancelRun()
+				return
+			}
+		}
+
+		if r.currentState() == StatePaused {
+			return
+		}
+
+		interval, err := r.mutate(func(i Interval) Interval {
+			i.ActualDuration += time.Second
+			return i
+		})
+		if err != nil {
+			done <- err
+			cancelRun()
+			return
+		}
+
+		onTick(interval)
+
+		if interval.ActualDuration < interval.PlannedDuration {
+			return
+		}
+
+		settled = true
+
+		if err := r.Transition(EventExpire); err != nil {
+			done <- err
+			cancelRun()
+			return
+		}
+
+		interval, err = r.lockedByID()
+		if err != nil {
+			done <- err
+			cancelRun()
+			return
+		}
+
+		end(interval)
+		done <- nil
+		cancelRun()
+	}, periodic.OnStop(func(periodic.Tick) {
+		if settled {
+			return
+		}
+
+		select {
+		case done <- r.Transition(EventCancel):
+		default:
+		}
+	}))
+
+	err = <-done
+	stopper.Stop()
+	return err
+}
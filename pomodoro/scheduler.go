@@ -0,0 +1,90 @@
+package pomodoro
+
+import (
+	"context"
+	"time"
+)
+
+// SchedulerDurations gives a Scheduler the planned duration to report
+// alongside whichever category it picks.
+type SchedulerDurations struct {
+	Pomodoro   time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+}
+
+func (d SchedulerDurations) forCategory(category string) time.Duration {
+	switch category {
+	case CategoryShortBreak:
+		return d.ShortBreak
+	case CategoryLongBreak:
+		return d.LongBreak
+	default:
+		return d.Pomodoro
+	}
+}
+
+// Scheduler decides what category the next interval should be, and how
+// long it should run for. IntervalConfig.Scheduler lets callers swap in
+// their own policy instead of the built-in count- or budget-based ones.
+type Scheduler interface {
+	Next(ctx context.Context, repo Repository) (string, time.Duration, error)
+}
+
+// CountScheduler is the original "long break after every N pomodoros"
+// policy, generalized so N (LongBreakEvery) is configurable rather than
+// hard-coded to 4.
+type CountScheduler struct {
+	LongBreakEvery int
+	Durations      SchedulerDurations
+}
+
+// Next implements Scheduler.
+func (s *CountScheduler) Next(ctx context.Context, repo Repository) (string, time.Duration, error) {
+	every := s.LongBreakEvery
+	if every <= 0 {
+		every = 4
+	}
+
+	category, err := nextCategoryN(repo, every)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return category, s.Durations.forCategory(category), nil
+}
+
+// DailyBudgetScheduler switches to long breaks once Repository.CountSince
+// reports DailyPomodoroBudget pomodoros started since midnight, rather than
+// after a fixed count of short breaks. That count covers every pomodoro
+// started today; cancelled ones are not excluded.
+type DailyBudgetScheduler struct {
+	DailyPomodoroBudget int
+	Durations           SchedulerDurations
+}
+
+// Next implements Scheduler.
+func (s *DailyBudgetScheduler) Next(ctx context.Context, repo Repository) (string, time.Duration, error) {
+	last, err := repo.Last()
+	if err != nil && err != ErrNoIntervals {
+		return "", 0, err
+	}
+
+	if err == ErrNoIntervals || last.Category != CategoryPomodoro {
+		return CategoryPomodoro, s.Durations.Pomodoro, nil
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	count, err := repo.CountSince(startOfDay, CategoryPomodoro)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if count >= s.DailyPomodoroBudget {
+		return CategoryLongBreak, s.Durations.LongBreak, nil
+	}
+
+	return CategoryShortBreak, s.Durations.ShortBreak, nil
+}